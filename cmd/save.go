@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/update"
+	"github.com/qri-io/qri/update/cron"
+	"github.com/spf13/cobra"
+)
+
+// NewSaveCommand creates a `qri save` cobra command
+func NewSaveCommand(ioStreams ioes.IOStreams) *cobra.Command {
+	o := &SaveOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "save",
+		Short: "save changes to a dataset",
+		Long: `save creates a new version of a dataset. when given a --period or
+--schedule flag, save also registers the dataset with the update service so
+it's re-saved on a recurring basis.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Period, "period", "", `iso8601 repeating interval to schedule this save on, eg "R/P1D"`)
+	cmd.Flags().StringVar(&o.Schedule, "schedule", "", `crontab expression to schedule this save on, eg "30 * * * *" or "@hourly"`)
+
+	return cmd
+}
+
+// SaveOptions encapsulates state for the save command
+type SaveOptions struct {
+	ioes.IOStreams
+
+	Ref      string
+	Period   string
+	Schedule string
+}
+
+// Complete adds any missing configuration that can only be added just
+// before calling Run
+func (o *SaveOptions) Complete(args []string) error {
+	if len(args) > 0 {
+		o.Ref = args[0]
+	}
+	return nil
+}
+
+// periodicity resolves the --period/--schedule flags into the single
+// periodicity string update.DatasetToJob expects. cron.NewSchedule already
+// knows how to tell an iso8601 interval apart from a crontab expression, so
+// the two flags are mutually exclusive ways of describing the same thing
+func (o *SaveOptions) periodicity() (string, error) {
+	switch {
+	case o.Period != "" && o.Schedule != "":
+		return "", fmt.Errorf("cannot use both --period and --schedule, pick one")
+	case o.Period != "":
+		return o.Period, nil
+	case o.Schedule != "":
+		return o.Schedule, nil
+	default:
+		return "", nil
+	}
+}
+
+// Run executes the save command
+func (o *SaveOptions) Run() error {
+	periodicity, err := o.periodicity()
+	if err != nil {
+		return err
+	}
+
+	ds := &dataset.Dataset{Commit: &dataset.Commit{}}
+	// TODO (b5) - actually save the dataset, this just wires up scheduling
+
+	if periodicity != "" {
+		job, err := update.DatasetToJob(ds, periodicity, nil)
+		if err != nil {
+			return err
+		}
+
+		// TODO (b5) - read the configured address from the active qri config
+		// instead of always falling back to the default
+		cli := cron.HTTPClient{Addr: config.DefaultUpdate().Address}
+		if err := cli.Ping(); err != nil {
+			return fmt.Errorf("update service isn't running, start it with `qri update service start`: %s", err)
+		}
+		if err := cli.Schedule(job); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "scheduled %q to run on %s\n", job.Name, job.Periodicity)
+	}
+
+	return nil
+}
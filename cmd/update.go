@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/update"
+	"github.com/qri-io/qri/update/cron"
+	"github.com/spf13/cobra"
+)
+
+// NewUpdateCommand creates a `qri update` cobra command and its
+// add/list/info/delete/run/logs subcommands for managing scheduled jobs
+func NewUpdateCommand(ioStreams ioes.IOStreams) *cobra.Command {
+	o := &UpdateOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "manage scheduled dataset & shell script updates",
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add [path]",
+		Short: "schedule a dataset or shell script for recurring updates",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Complete()
+			return o.Add(args[0])
+		},
+	}
+	addCmd.Flags().StringVar(&o.Period, "period", "", `iso8601 repeating interval, eg "R/P1D"`)
+	addCmd.Flags().StringVar(&o.Schedule, "schedule", "", `crontab expression, eg "30 * * * *" or "@hourly"`)
+
+	cmd.AddCommand(
+		addCmd,
+		&cobra.Command{
+			Use:   "list",
+			Short: "list scheduled jobs",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				o.Complete()
+				return o.List()
+			},
+		},
+		&cobra.Command{
+			Use:   "info [name]",
+			Short: "show details for a scheduled job",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				o.Complete()
+				return o.Info(args[0])
+			},
+		},
+		&cobra.Command{
+			Use:   "delete [name]",
+			Short: "remove a scheduled job",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				o.Complete()
+				return o.Delete(args[0])
+			},
+		},
+		&cobra.Command{
+			Use:   "run [name]",
+			Short: "run a scheduled job immediately",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				o.Complete()
+				return o.Run(args[0])
+			},
+		},
+		newUpdateLogsCommand(o),
+	)
+
+	return cmd
+}
+
+func newUpdateLogsCommand(o *UpdateOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs [name]",
+		Short: "show a scheduled job's run history",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Complete()
+			return o.Logs(args[0])
+		},
+	}
+	cmd.Flags().IntVar(&o.Tail, "tail", 10, "number of log entries to show")
+	cmd.Flags().BoolVar(&o.Follow, "follow", false, "stream new log entries as they're written")
+	return cmd
+}
+
+// UpdateOptions encapsulates state for the update subcommands
+type UpdateOptions struct {
+	ioes.IOStreams
+
+	Period   string
+	Schedule string
+	Tail     int
+	Follow   bool
+
+	cli cron.HTTPClient
+}
+
+// Complete finishes configuring UpdateOptions, connecting to the running
+// update service
+func (o *UpdateOptions) Complete() {
+	// TODO (b5) - read the configured address from the active qri config
+	// instead of always falling back to the default
+	o.cli = cron.HTTPClient{Addr: config.DefaultUpdate().Address}
+}
+
+// periodicity resolves the --period/--schedule flags into the single
+// periodicity string update.DatasetToJob/ShellScriptToJob expect. the two
+// flags are mutually exclusive ways of describing the same thing, same as
+// SaveOptions.periodicity
+func (o *UpdateOptions) periodicity() (string, error) {
+	switch {
+	case o.Period != "" && o.Schedule != "":
+		return "", fmt.Errorf("cannot use both --period and --schedule, pick one")
+	case o.Period != "":
+		return o.Period, nil
+	case o.Schedule != "":
+		return o.Schedule, nil
+	default:
+		return "", nil
+	}
+}
+
+// Add schedules a dataset reference or shell script path for recurring
+// updates, via either a --period or --schedule expression
+func (o *UpdateOptions) Add(path string) error {
+	periodicity, err := o.periodicity()
+	if err != nil {
+		return err
+	}
+
+	var job *cron.Job
+	if update.PossibleShellScript(path) {
+		job, err = update.ShellScriptToJob(path, periodicity, nil)
+	} else {
+		job, err = update.DatasetToJob(&dataset.Dataset{Peername: "me", Name: path, Commit: &dataset.Commit{}}, periodicity, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := o.cli.Schedule(job); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "scheduled %q to run on %s\n", job.Name, job.Periodicity)
+	return nil
+}
+
+// List prints all scheduled jobs
+func (o *UpdateOptions) List() error {
+	jobs, err := o.cli.Jobs(0, 0)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		fmt.Fprintf(o.Out, "%s\t%s\t%s\n", job.Name, job.Type, job.Periodicity)
+	}
+	return nil
+}
+
+// Info prints a single job's details
+func (o *UpdateOptions) Info(name string) error {
+	job, err := o.cli.Job(name)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "%s\t%s\t%s\tlast run: %s\n", job.Name, job.Type, job.Periodicity, job.PrevRunStart)
+	return nil
+}
+
+// Delete removes a scheduled job
+func (o *UpdateOptions) Delete(name string) error {
+	if err := o.cli.Unschedule(name); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "deleted %q\n", name)
+	return nil
+}
+
+// Run triggers an immediate execution of a scheduled job
+func (o *UpdateOptions) Run(name string) error {
+	job, err := o.cli.RunJob(name)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "ran %q\n", job.Name)
+	return nil
+}
+
+// Logs prints (and optionally follows) a job's run history
+func (o *UpdateOptions) Logs(name string) error {
+	return o.cli.Logs(context.Background(), name, o.Tail, o.Follow, o.Out)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import "testing"
+
+func TestUpdateOptionsPeriodicity(t *testing.T) {
+	cases := []struct {
+		name     string
+		period   string
+		schedule string
+		want     string
+		wantErr  bool
+	}{
+		{"neither set", "", "", "", false},
+		{"period only", "R/P1D", "", "R/P1D", false},
+		{"schedule only", "", "@hourly", "@hourly", false},
+		{"both set", "R/P1D", "@hourly", "", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := &UpdateOptions{Period: c.period, Schedule: c.schedule}
+			got, err := o.periodicity()
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("expected an error when both --period and --schedule are set")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != c.want {
+				t.Errorf("periodicity mismatch. want: %q got: %q", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Topic is the set of event types a Bus dispatches
+type Topic string
+
+// Event is the data structure transmitted on a Bus, pairing the topic it
+// fired for with a topic-specific payload
+type Event struct {
+	Topic     Topic
+	Timestamp int64
+	Payload   interface{}
+}
+
+// Handler is a function that can be registered with a Bus to react to
+// events published on one or more topics
+type Handler func(ctx context.Context, e Event) error
+
+// Bus is a central clearinghouse for system events, decoupling the
+// component that knows something happened (eg the update service) from
+// the components that need to react to it (FSI, lib, notifiers, webhooks,
+// UI)
+type Bus interface {
+	// Publish sends an event for topic, calling every handler subscribed
+	// to it
+	Publish(ctx context.Context, topic Topic, payload interface{}) error
+	// Subscribe registers handler to be called whenever an event fires on
+	// any of the given topics
+	Subscribe(handler Handler, topics ...Topic)
+}
+
+// NewBus creates an in-memory event Bus
+func NewBus(ctx context.Context) Bus {
+	return &bus{
+		ctx:      ctx,
+		handlers: map[Topic][]Handler{},
+	}
+}
+
+type bus struct {
+	ctx  context.Context
+	lock sync.Mutex
+
+	handlers map[Topic][]Handler
+}
+
+// assert bus satisfies the Bus interface at compile time
+var _ Bus = (*bus)(nil)
+
+func (b *bus) Publish(ctx context.Context, topic Topic, payload interface{}) error {
+	b.lock.Lock()
+	handlers := append([]Handler{}, b.handlers[topic]...)
+	b.lock.Unlock()
+
+	e := Event{
+		Topic:     topic,
+		Timestamp: time.Now().UnixNano(),
+		Payload:   payload,
+	}
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("handling %q event: %v", topic, errs[0])
+	}
+	return nil
+}
+
+func (b *bus) Subscribe(handler Handler, topics ...Topic) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, topic := range topics {
+		b.handlers[topic] = append(b.handlers[topic], handler)
+	}
+}
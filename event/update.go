@@ -0,0 +1,25 @@
+package event
+
+import "time"
+
+var (
+	// ETUpdateJobScheduled fires when a job is added to the update service
+	ETUpdateJobScheduled = Topic("update:jobScheduled")
+	// ETUpdateJobStarted fires when the update service begins running a job
+	ETUpdateJobStarted = Topic("update:jobStarted")
+	// ETUpdateJobSucceeded fires when a job's run completes without error
+	ETUpdateJobSucceeded = Topic("update:jobSucceeded")
+	// ETUpdateJobFailed fires when a job's run completes with an error
+	ETUpdateJobFailed = Topic("update:jobFailed")
+	// ETUpdateJobNoChanges fires when a dataset job's run completes but
+	// found nothing new to save
+	ETUpdateJobNoChanges = Topic("update:jobNoChanges")
+)
+
+// UpdateJobEvent is the payload carried by ETUpdateJob* events
+type UpdateJobEvent struct {
+	Name     string
+	RunID    int
+	Duration time.Duration
+	Err      string
+}
@@ -85,6 +85,13 @@ func (n *QriNode) handleConnected(ws *WrappedStream, msg Message) (hangup bool)
 		log.Debug(err.Error())
 		return
 	}
+
+	// snapshot the peer list under ConnectedQriPeerIDs' own read lock before
+	// doing anything that writes to the repo profile store below. taking the
+	// snapshot after RequestProfile used to race a concurrent iteration of
+	// & write to the profile store, corrupting it
+	pids := n.ConnectedQriPeerIDs()
+
 	n.host.Peerstore().AddAddrs(pinfo.ID, pinfo.Addrs, pstore.TempAddrTTL)
 
 	// request this peer's profile to connect two node's knowledge of each other
@@ -93,13 +100,12 @@ func (n *QriNode) handleConnected(ws *WrappedStream, msg Message) (hangup bool)
 		return
 	}
 
-	// forward this message to all connected peers except the sender
-	// TODO - this is causing concurrent iteration & write to the repo profile store. Fix
-	// pids := peerDifference(n.ConnectedQriPeerIDs(), []peer.ID{pinfo.ID})
-	// if err := n.SendMessage(msg, nil, pids...); err != nil {
-	// 	log.Debug(err.Error())
-	// 	return
-	// }
+	// forward this message to all connected peers except the sender, using
+	// the snapshot taken above rather than re-querying connected peers
+	if err := n.SendMessage(msg, nil, peerDifference(pids, []peer.ID{pinfo.ID})...); err != nil {
+		log.Debug(err.Error())
+		return
+	}
 
 	// store that we've seen this message, cleaning up after a while
 	n.msgState.Store(msg.ID, true)
@@ -110,3 +116,19 @@ func (n *QriNode) handleConnected(ws *WrappedStream, msg Message) (hangup bool)
 
 	return
 }
+
+// peerDifference returns the peer IDs in a that are not present in b
+func peerDifference(a, b []peer.ID) []peer.ID {
+	exclude := make(map[peer.ID]bool, len(b))
+	for _, id := range b {
+		exclude[id] = true
+	}
+
+	diff := make([]peer.ID, 0, len(a))
+	for _, id := range a {
+		if !exclude[id] {
+			diff = append(diff, id)
+		}
+	}
+	return diff
+}
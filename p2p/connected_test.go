@@ -0,0 +1,23 @@
+package p2p
+
+import (
+	"reflect"
+	"testing"
+
+	peer "gx/ipfs/QmdVrMn1LhB4ybb8hMVaMLXnA8XRSewMnK6YqXKXoTcRvN/go-libp2p-peer"
+)
+
+func TestPeerDifference(t *testing.T) {
+	a := []peer.ID{"a", "b", "c"}
+	b := []peer.ID{"b"}
+
+	got := peerDifference(a, b)
+	want := []peer.ID{"a", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("peerDifference mismatch. want: %v got: %v", want, got)
+	}
+
+	if got := peerDifference(a, nil); !reflect.DeepEqual(got, a) {
+		t.Errorf("expected peerDifference with an empty exclude list to return every id, got: %v", got)
+	}
+}
@@ -0,0 +1,113 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/qri-io/qri/dsref"
+)
+
+// MtDatasetUpdated announces a peer has saved a new version of a dataset
+const MtDatasetUpdated = MsgType("dataset_updated")
+
+// datasetUpdatedPod is the on-the-wire representation of a dataset update
+// announcement
+type datasetUpdatedPod struct {
+	Ref     string
+	NewHead string
+}
+
+// AnnounceDatasetUpdated kicks off a notice to connected peers that ref
+// has been saved at newHead, allowing peers to pull the new version
+// without waiting on periodic discovery
+func (n *QriNode) AnnounceDatasetUpdated(ref dsref.Ref, newHead string) error {
+	pids := n.ConnectedQriPeerIDs()
+	log.Debugf("%s AnnounceDatasetUpdated %s@%s to %d peers", n.ID, ref, newHead, len(pids))
+
+	data, err := json.Marshal(datasetUpdatedPod{
+		Ref:     ref.String(),
+		NewHead: newHead,
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := NewMessage(n.ID, MtDatasetUpdated, data)
+
+	go func() {
+		if err := n.SendMessage(msg, nil, pids...); err != nil {
+			log.Debugf("send dataset updated message error: %s", err.Error())
+		}
+	}()
+
+	return nil
+}
+
+// handleDatasetUpdated processes an incoming MtDatasetUpdated message,
+// gossip-forwarding it to other connected peers (deduped via msgState,
+// same as handleConnected) and pulling the new version locally via
+// pullDatasetUpdate when we already have an earlier one
+func (n *QriNode) handleDatasetUpdated(ws *WrappedStream, msg Message) (hangup bool) {
+	if _, ok := n.msgState.Load(msg.ID); ok {
+		return
+	}
+
+	dup := datasetUpdatedPod{}
+	if err := json.Unmarshal(msg.Body, &dup); err != nil {
+		log.Debug(err.Error())
+		return
+	}
+
+	// snapshot connected peers before forwarding, same fix applied to
+	// handleConnected: take the list once, up front, rather than
+	// re-querying after any state-mutating work below
+	pids := n.ConnectedQriPeerIDs()
+
+	ref, err := dsref.Parse(dup.Ref)
+	if err != nil {
+		log.Debug(err.Error())
+		return
+	}
+
+	// pulling can take a while, don't hold up gossip-forwarding on it
+	go n.pullDatasetUpdate(ref, dup.NewHead)
+
+	if err := n.SendMessage(msg, nil, pids...); err != nil {
+		log.Debug(err.Error())
+		return
+	}
+
+	n.msgState.Store(msg.ID, true)
+	go func(id string) {
+		<-time.After(time.Minute)
+		n.msgState.Delete(id)
+	}(msg.ID)
+
+	return
+}
+
+// pullDatasetUpdate fetches a newly announced dataset version from the
+// network, replicating it without waiting for periodic discovery. it only
+// pulls when we already have some version of ref stored locally & its path
+// differs from newHead - an announcement alone shouldn't turn into an
+// unsolicited download for every peer that merely saw the gossip
+func (n *QriNode) pullDatasetUpdate(ref dsref.Ref, newHead string) {
+	ctx := context.Background()
+
+	resolved := ref
+	if _, err := n.Repo.Resolve(ctx, &resolved); err != nil {
+		// we don't have a local version of this dataset - nothing to update
+		return
+	}
+
+	if resolved.Path == newHead {
+		// already up to date
+		return
+	}
+
+	ref.Path = newHead
+	if _, err := n.RequestDataset(&ref); err != nil {
+		log.Debugf("pulling updated dataset %s: %s", ref, err)
+	}
+}
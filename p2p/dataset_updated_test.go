@@ -0,0 +1,27 @@
+package p2p
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestDatasetUpdatedPodRoundTrip guards the on-the-wire shape
+// handleDatasetUpdated depends on to recover a dsref and new head from a
+// gossiped message body
+func TestDatasetUpdatedPodRoundTrip(t *testing.T) {
+	pod := datasetUpdatedPod{Ref: "me/mydataset", NewHead: "/ipfs/QmHead"}
+
+	data, err := json.Marshal(pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := datasetUpdatedPod{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != pod {
+		t.Errorf("round trip mismatch. want: %+v got: %+v", pod, got)
+	}
+}
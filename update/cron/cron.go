@@ -0,0 +1,171 @@
+// Package cron schedules the running of dataset updates and shell scripts
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	golog "github.com/ipfs/go-log"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/event"
+)
+
+var log = golog.Logger("cron")
+
+// RunJobFunc is a function for executing a job. Cron takes a RunJobFunc
+// and deals with the scheduling, passing time & context to the runner
+type RunJobFunc func(ctx context.Context, streams ioes.IOStreams, job *Job) error
+
+// Factory is a function that constructs a RunJobFunc, giving callers a
+// chance to close over the context a batch of jobs is run within. the
+// provided StatsManager lets the returned RunJobFunc record status
+// transitions as it drives a job's underlying command
+type Factory func(ctx context.Context, stats *StatsManager) RunJobFunc
+
+// JobStore persists Jobs
+type JobStore interface {
+	// Jobs lists jobs currently in the store, sorted by Name
+	Jobs(offset, limit int) ([]*Job, error)
+	// Job fetches a single job by name
+	Job(name string) (*Job, error)
+	// PutJob places a job in the store, overwriting any existing job with
+	// the same name
+	PutJob(job *Job) error
+	// DeleteJob removes a job from the store
+	DeleteJob(name string) error
+}
+
+// Cron coordinates the scheduled execution of jobs
+type Cron struct {
+	jobStore JobStore
+	logStore JobStore
+	factory  Factory
+	stats    *StatsManager
+	bus      event.Bus
+
+	// running tracks job names currently executing, preventing a scheduler
+	// tick from racing a manual run (or webhook trigger) of the same job
+	running sync.Map
+}
+
+// NewCron creates a Cron that reads jobs from jobStore, records completed
+// runs to logStore, and executes jobs with runners built by factory
+func NewCron(jobStore, logStore JobStore, factory Factory) *Cron {
+	return &Cron{
+		jobStore: jobStore,
+		logStore: logStore,
+		factory:  factory,
+		stats:    NewStatsManager(),
+	}
+}
+
+// SetBus attaches an event.Bus that Cron publishes ETUpdateJobScheduled
+// events to as jobs are added. a Cron with no bus attached publishes
+// nothing
+func (c *Cron) SetBus(bus event.Bus) {
+	c.bus = bus
+}
+
+// Status returns the status of a job's most recent run
+func (c *Cron) Status(name string) (RunStatus, error) {
+	return c.stats.Status(name)
+}
+
+// History returns up to limit of a job's most recent runs, newest first
+func (c *Cron) History(name string, limit int) ([]*RunInfo, error) {
+	return c.stats.History(name, limit)
+}
+
+// Start begins the scheduler loop, blocking until ctx is cancelled
+func (c *Cron) Start(ctx context.Context) error {
+	runner := c.factory(ctx, c.stats)
+
+	for {
+		wait := c.checkJobs(ctx, runner)
+		select {
+		case <-time.After(wait):
+			continue
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// checkJobs runs any jobs whose schedule has elapsed, returning a duration
+// to wait before the next job is due
+func (c *Cron) checkJobs(ctx context.Context, runner RunJobFunc) time.Duration {
+	jobs, err := c.jobStore.Jobs(0, 0)
+	if err != nil {
+		log.Errorf("listing jobs: %s", err)
+		return time.Minute
+	}
+
+	next := time.Minute
+	now := time.Now()
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+
+	for _, job := range jobs {
+		// dispatch on schedule type is handled entirely by the Schedule
+		// interface: every implementation (iso8601 or crontab) knows how
+		// to compute its own next fire time
+		fireAt := job.Periodicity.Next(job.PrevRunStart)
+		if fireAt.After(now) {
+			if until := fireAt.Sub(now); until < next {
+				next = until
+			}
+			continue
+		}
+
+		go c.runJob(ctx, runner, job)
+	}
+
+	return next
+}
+
+// runJob executes job, guarding against a second concurrent execution of
+// the same job name (eg a scheduler tick racing a manual RunJob call). job
+// itself is never mutated - a copy is advanced to PrevRunStart and passed
+// to the runner & stores, since JobStore implementations may hand back a
+// pointer they also hold onto internally
+func (c *Cron) runJob(ctx context.Context, runner RunJobFunc, job *Job) *Job {
+	if _, running := c.running.LoadOrStore(job.Name, struct{}{}); running {
+		log.Debugf("job %q is already running, skipping", job.Name)
+		return job
+	}
+	defer c.running.Delete(job.Name)
+
+	next := *job
+	next.PrevRunStart = time.Now()
+	if err := c.jobStore.PutJob(&next); err != nil {
+		log.Errorf("updating job %q prev run start: %s", job.Name, err)
+	}
+
+	streams := ioes.NewDiscardIOStreams()
+	if err := runner(ctx, streams, &next); err != nil {
+		log.Errorf("running job %q: %s", job.Name, err)
+	}
+
+	if c.logStore != nil {
+		if err := c.logStore.PutJob(&next); err != nil {
+			log.Errorf("logging job %q: %s", job.Name, err)
+		}
+	}
+
+	return &next
+}
+
+// RunJob runs the named job immediately, regardless of its schedule. it
+// blocks until the run completes. if the job is already mid-run (eg the
+// scheduler beat it to it), RunJob returns without running it again
+func (c *Cron) RunJob(ctx context.Context, name string) (*Job, error) {
+	job, err := c.jobStore.Job(name)
+	if err != nil {
+		return nil, fmt.Errorf("getting job %q: %w", name, err)
+	}
+
+	return c.runJob(ctx, c.factory(ctx, c.stats), job), nil
+}
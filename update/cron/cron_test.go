@@ -0,0 +1,105 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qri-io/ioes"
+)
+
+// TestRunJobSkipsConcurrentRun guards against two overlapping executions of
+// the same job (eg a scheduler tick racing a manual RunJob call) both
+// driving the runner and racing writes to PrevRunStart
+func TestRunJobSkipsConcurrentRun(t *testing.T) {
+	var running int32
+	var mu sync.Mutex
+	maxConcurrent := 0
+
+	factory := Factory(func(ctx context.Context, stats *StatsManager) RunJobFunc {
+		return func(ctx context.Context, streams ioes.IOStreams, job *Job) error {
+			mu.Lock()
+			running++
+			if int(running) > maxConcurrent {
+				maxConcurrent = int(running)
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		}
+	})
+
+	c := NewCron(&MemJobStore{}, &MemJobStore{}, factory)
+	sched, err := NewSchedule("@hourly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := &Job{Name: "me/mydataset", Type: JTShellScript, Periodicity: sched}
+	if err := c.jobStore.PutJob(job); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := c.factory(context.Background(), c.stats)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.runJob(context.Background(), runner, job)
+		}()
+	}
+	wg.Wait()
+
+	if maxConcurrent > 1 {
+		t.Errorf("expected at most 1 concurrent run of the same job, observed %d", maxConcurrent)
+	}
+}
+
+// TestMemJobStoreReturnsCopies confirms Jobs/Job hand back copies, so a
+// caller mutating a returned Job (eg setting PrevRunStart) can't race the
+// store's own internal access to its slice
+func TestMemJobStoreReturnsCopies(t *testing.T) {
+	s := &MemJobStore{}
+	sched, err := NewSchedule("@hourly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutJob(&Job{Name: "me/mydataset", Periodicity: sched}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Job("me/mydataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.PrevRunStart = time.Now()
+
+	again, err := s.Job("me/mydataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.PrevRunStart.IsZero() {
+		t.Error("expected mutating a Job returned by Job() not to affect the store's internal copy")
+	}
+
+	jobs, err := s.Jobs(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs[0].PrevRunStart = time.Now()
+
+	again2, err := s.Job("me/mydataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again2.PrevRunStart.IsZero() {
+		t.Error("expected mutating a Job returned by Jobs() not to affect the store's internal copy")
+	}
+}
@@ -0,0 +1,365 @@
+package cron
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qri-io/qri/event"
+)
+
+// ServeHTTP starts an HTTP server exposing the cron service, used by the
+// `qri update` CLI subcommands (add/list/info/delete/run/logs) to manage
+// jobs on a running service
+func (c *Cron) ServeHTTP(addr string) error {
+	m := http.NewServeMux()
+	m.HandleFunc("/", c.statusHandler)
+	m.HandleFunc("/jobs", c.jobsHandler)
+	m.HandleFunc("/jobs/", c.jobHandler)
+	m.HandleFunc("/webhook/", c.webhookHandler)
+	log.Debugf("cron service listening on %s", addr)
+	return http.ListenAndServe(addr, m)
+}
+
+func (c *Cron) statusHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// jobsHandler handles listing (GET) & scheduling (POST) of jobs at /jobs
+func (c *Cron) jobsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		jobs, err := c.jobStore.Jobs(0, 0)
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		jsonResponse(w, jobs)
+	case http.MethodPost:
+		job := &Job{}
+		if err := json.NewDecoder(r.Body).Decode(job); err != nil {
+			httpError(w, err)
+			return
+		}
+		if err := job.Validate(); err != nil {
+			httpError(w, err)
+			return
+		}
+		if err := c.jobStore.PutJob(job); err != nil {
+			httpError(w, err)
+			return
+		}
+		if c.bus != nil {
+			c.bus.Publish(r.Context(), event.ETUpdateJobScheduled, event.UpdateJobEvent{Name: job.Name})
+		}
+		jsonResponse(w, job)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// jobHandler handles info (GET), delete (DELETE), run (POST), logs (GET),
+// status (GET) and history (GET) for a single job at /jobs/{name}. job
+// names themselves routinely contain slashes (every dataset job is named
+// "<peername>/<dsname>"), so the entire path remainder is taken as the
+// name - the action is disambiguated via an "action" query param instead
+// of a path suffix, which a job name could otherwise collide with (eg a
+// dataset literally named "me/status")
+func (c *Cron) jobHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	if name == "" {
+		http.Error(w, "job name is required", http.StatusBadRequest)
+		return
+	}
+
+	switch action := r.URL.Query().Get("action"); action {
+	case "run":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		job, err := c.RunJob(r.Context(), name)
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		jsonResponse(w, job)
+	case "logs":
+		c.logsHandler(w, r, name)
+	case "status":
+		status, err := c.Status(name)
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		jsonResponse(w, status)
+	case "history":
+		limit := 0
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			limit, _ = strconv.Atoi(raw)
+		}
+		history, err := c.History(name, limit)
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		jsonResponse(w, history)
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			job, err := c.jobStore.Job(name)
+			if err != nil {
+				httpError(w, err)
+				return
+			}
+			jsonResponse(w, job)
+		case http.MethodDelete:
+			if err := c.jobStore.DeleteJob(name); err != nil {
+				httpError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.Error(w, fmt.Sprintf("unrecognized action %q", action), http.StatusBadRequest)
+	}
+}
+
+// logsHandler writes a job's run history from the StatsManager, honoring
+// the tail query param and optionally following for new runs as they
+// complete. entries are written oldest-first, one JSON object per line
+func (c *Cron) logsHandler(w http.ResponseWriter, r *http.Request, name string) {
+	tail := 10
+	if raw := r.URL.Query().Get("tail"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			tail = n
+		}
+	}
+	follow := r.URL.Query().Get("follow") == "true"
+
+	flusher, canFlush := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	lastRunID := 0
+
+	// writeNew emits any run with a RunID greater than lastRunID, oldest
+	// first (History returns newest-first), advancing lastRunID as it goes
+	writeNew := func(limit int) {
+		history, err := c.stats.History(name, limit)
+		if err != nil {
+			return
+		}
+		for i := len(history) - 1; i >= 0; i-- {
+			info := history[i]
+			if info.RunID <= lastRunID {
+				continue
+			}
+			enc.Encode(info)
+			lastRunID = info.RunID
+		}
+	}
+
+	writeNew(tail)
+	if !follow || !canFlush {
+		return
+	}
+
+	flusher.Flush()
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+			before := lastRunID
+			writeNew(0)
+			if lastRunID != before {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func jsonResponse(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func httpError(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// HTTPClient accesses the cron service over HTTP, backing the `qri update`
+// CLI subcommands
+type HTTPClient struct {
+	Addr string
+}
+
+// Ping confirms a cron service is up and accepting requests at Addr
+func (cli HTTPClient) Ping() error {
+	resp, err := http.Get(fmt.Sprintf("http://%s/", cli.Addr))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cron service ping failed with status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Jobs lists scheduled jobs
+func (cli HTTPClient) Jobs(offset, limit int) ([]*Job, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/jobs?offset=%d&limit=%d", cli.Addr, offset, limit))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeErr(resp)
+	}
+
+	jobs := []*Job{}
+	err = json.NewDecoder(resp.Body).Decode(&jobs)
+	return jobs, err
+}
+
+// Job fetches a single scheduled job's details
+func (cli HTTPClient) Job(name string) (*Job, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/jobs/%s", cli.Addr, name))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeErr(resp)
+	}
+
+	job := &Job{}
+	err = json.NewDecoder(resp.Body).Decode(job)
+	return job, err
+}
+
+// Schedule adds a job to the cron service
+func (cli HTTPClient) Schedule(job *Job) error {
+	body, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(fmt.Sprintf("http://%s/jobs", cli.Addr), "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return decodeErr(resp)
+	}
+	return nil
+}
+
+// Unschedule removes a job from the cron service
+func (cli HTTPClient) Unschedule(name string) error {
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("http://%s/jobs/%s", cli.Addr, name), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return decodeErr(resp)
+	}
+	return nil
+}
+
+// RunJob triggers an immediate run of a scheduled job, regardless of its
+// next scheduled time
+func (cli HTTPClient) RunJob(name string) (*Job, error) {
+	resp, err := http.Post(fmt.Sprintf("http://%s/jobs/%s?action=run", cli.Addr, name), "application/json", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeErr(resp)
+	}
+
+	job := &Job{}
+	err = json.NewDecoder(resp.Body).Decode(job)
+	return job, err
+}
+
+// Status fetches the status of a job's most recent run
+func (cli HTTPClient) Status(name string) (RunStatus, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/jobs/%s?action=status", cli.Addr, name))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", decodeErr(resp)
+	}
+
+	var status RunStatus
+	err = json.NewDecoder(resp.Body).Decode(&status)
+	return status, err
+}
+
+// History fetches up to limit of a job's most recent runs, newest first
+func (cli HTTPClient) History(name string, limit int) ([]*RunInfo, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/jobs/%s?action=history&limit=%d", cli.Addr, name, limit))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeErr(resp)
+	}
+
+	history := []*RunInfo{}
+	err = json.NewDecoder(resp.Body).Decode(&history)
+	return history, err
+}
+
+// Logs streams a job's run history to w, tailing the last `tail` entries
+// and following for new entries if follow is true. Logs blocks until ctx
+// is cancelled when follow is true
+func (cli HTTPClient) Logs(ctx context.Context, name string, tail int, follow bool, w io.Writer) error {
+	url := fmt.Sprintf("http://%s/jobs/%s?action=logs&tail=%s&follow=%t", cli.Addr, name, strconv.Itoa(tail), follow)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return decodeErr(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fmt.Fprintln(w, scanner.Text())
+	}
+	return scanner.Err()
+}
+
+func decodeErr(resp *http.Response) error {
+	buf := make([]byte, 512)
+	n, _ := resp.Body.Read(buf)
+	return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(buf[:n])))
+}
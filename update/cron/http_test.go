@@ -0,0 +1,175 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qri-io/ioes"
+)
+
+func newTestCron() *Cron {
+	factory := Factory(func(ctx context.Context, stats *StatsManager) RunJobFunc {
+		return func(ctx context.Context, streams ioes.IOStreams, job *Job) error {
+			run := stats.StartRun(job.Name)
+			stats.MarkRunning(run)
+			stats.FinishRun(run, nil, 0, "")
+			return nil
+		}
+	})
+	return NewCron(&MemJobStore{}, &MemJobStore{}, factory)
+}
+
+func mustSchedule(t *testing.T, c *Cron, name string) {
+	t.Helper()
+	sched, err := NewSchedule("@hourly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.jobStore.PutJob(&Job{Name: name, Type: JTShellScript, Periodicity: sched}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestJobHandlerNameWithSlash guards against the path-splitting bug where a
+// job name containing slashes (the default "<peername>/<dsname>" form) was
+// misparsed as name/action
+func TestJobHandlerNameWithSlash(t *testing.T) {
+	c := newTestCron()
+	mustSchedule(t, c, "me/mydataset")
+
+	s := httptest.NewServer(http.HandlerFunc(c.jobHandler))
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "/jobs/me/mydataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	job := &Job{}
+	if err := json.NewDecoder(resp.Body).Decode(job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Name != "me/mydataset" {
+		t.Errorf("name mismatch. want: %q got: %q", "me/mydataset", job.Name)
+	}
+}
+
+// TestJobHandlerActionQueryParam confirms the "action" query param is
+// recognized alongside slash-bearing names
+func TestJobHandlerActionQueryParam(t *testing.T) {
+	c := newTestCron()
+	mustSchedule(t, c, "me/mydataset")
+	// Status reports an error for a job with no recorded runs, so record one
+	run := c.stats.StartRun("me/mydataset")
+	c.stats.MarkRunning(run)
+	c.stats.FinishRun(run, nil, 0, "")
+
+	s := httptest.NewServer(http.HandlerFunc(c.jobHandler))
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "/jobs/me/mydataset?action=status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+// TestJobHandlerNameCollidingWithAction guards against a job whose name
+// component happens to match a recognized action (eg a dataset literally
+// named "me/status") being misrouted - the name is the entire path
+// remainder regardless of what it looks like, since the action only ever
+// comes from the query param
+func TestJobHandlerNameCollidingWithAction(t *testing.T) {
+	c := newTestCron()
+	mustSchedule(t, c, "me/status")
+
+	s := httptest.NewServer(http.HandlerFunc(c.jobHandler))
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "/jobs/me/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	job := &Job{}
+	if err := json.NewDecoder(resp.Body).Decode(job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Name != "me/status" {
+		t.Errorf("name mismatch. want: %q got: %q", "me/status", job.Name)
+	}
+}
+
+// TestJobHandlerRunRequiresPost confirms a GET to the run action is
+// rejected rather than triggering an execution as a side effect of a safe
+// HTTP verb
+func TestJobHandlerRunRequiresPost(t *testing.T) {
+	c := newTestCron()
+	mustSchedule(t, c, "me/mydataset")
+
+	s := httptest.NewServer(http.HandlerFunc(c.jobHandler))
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "/jobs/me/mydataset?action=run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET .../?action=run, got %d", resp.StatusCode)
+	}
+
+	if status, _ := c.Status("me/mydataset"); status != "" {
+		t.Errorf("expected no run to have been recorded, got status %q", status)
+	}
+}
+
+// TestLogsHandlerTail confirms the tail query param limits the number of
+// entries returned, reading from the StatsManager rather than the job
+// store's persisted record
+func TestLogsHandlerTail(t *testing.T) {
+	c := newTestCron()
+	for i := 0; i < 3; i++ {
+		run := c.stats.StartRun("me/mydataset")
+		c.stats.MarkRunning(run)
+		c.stats.FinishRun(run, nil, 0, "")
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.logsHandler(w, r, "me/mydataset")
+	}))
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "?tail=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	count := 0
+	for {
+		info := &RunInfo{}
+		if err := dec.Decode(info); err != nil {
+			break
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("expected 2 log entries, got %d", count)
+	}
+}
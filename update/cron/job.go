@@ -0,0 +1,70 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+)
+
+// JobType is the set of things a cron job can run
+type JobType string
+
+const (
+	// JTDataset represents a "qri save" command
+	JTDataset = JobType("dataset")
+	// JTShellScript represents an executable shell script
+	JTShellScript = JobType("shell")
+)
+
+// Job represents a scheduled event, with fields necessary for the update
+// service to execute it and keep track of its recurrence
+type Job struct {
+	Name         string
+	Type         JobType
+	Periodicity  Schedule
+	PrevRunStart time.Time
+	RepoPath     string
+	Options      interface{}
+	Webhook      *WebhookConfig
+}
+
+// WebhookConfig configures a job to additionally be triggerable via the
+// cron service's webhook endpoint, instead of only firing on its schedule
+type WebhookConfig struct {
+	// Secret is used to validate the HMAC signature of incoming webhook
+	// requests, sent in the X-Qri-Signature header
+	Secret string
+	// AllowedIPs restricts which source IPs may trigger this job's webhook.
+	// an empty list allows any source
+	AllowedIPs []string
+}
+
+// Validate confirms a Job has the values required to be scheduled & run
+func (j *Job) Validate() error {
+	if j.Name == "" {
+		return fmt.Errorf("job name is required")
+	}
+	if j.Type != JTDataset && j.Type != JTShellScript {
+		return fmt.Errorf("unrecognized job type: %q", j.Type)
+	}
+	if j.Periodicity == nil {
+		return fmt.Errorf("job periodicity is required")
+	}
+	return nil
+}
+
+// DatasetOptions encapsulates options passed to "qri save" for dataset jobs
+type DatasetOptions struct {
+	Title                string
+	Message              string
+	Recall               string
+	BodyPath             string
+	FilePaths            []string
+	Publish              bool
+	Strict               bool
+	Force                bool
+	ConvertFormatToPrev  bool
+	ShouldRender         bool
+}
+
+// ShellScriptOptions encapsulates options passed to a shell script job
+type ShellScriptOptions struct{}
@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qri-io/iso8601"
+	cronexpr "github.com/robfig/cron/v3"
+)
+
+// Schedule is the generalized interface for describing recurring points in
+// time. it's implemented by both ISO-8601 repeating intervals and crontab
+// expressions so the rest of the package can work with periodicity without
+// caring which syntax a user described it in
+type Schedule interface {
+	fmt.Stringer
+	// Next returns the next time the schedule should fire, given the last
+	// time it fired (or the zero time if it's never fired)
+	Next(prev time.Time) time.Time
+}
+
+// NewSchedule parses a periodicity string into a Schedule. ISO-8601
+// repeating intervals (eg "R/P1D") are tried first, falling back to
+// crontab syntax (5/6 field expressions and "@hourly"-style descriptors)
+// on failure
+func NewSchedule(periodicity string) (Schedule, error) {
+	if ri, err := iso8601.ParseRepeatingInterval(periodicity); err == nil {
+		return iso8601Schedule{ri}, nil
+	}
+
+	sched, err := cronexpr.ParseStandard(periodicity)
+	if err != nil {
+		return nil, fmt.Errorf("invalid periodicity %q: not a valid iso8601 interval or cron expression: %w", periodicity, err)
+	}
+	return crontabSchedule{expr: periodicity, sched: sched}, nil
+}
+
+// iso8601Schedule is a Schedule backed by an iso8601.RepeatingInterval
+type iso8601Schedule struct {
+	ri iso8601.RepeatingInterval
+}
+
+// assert iso8601Schedule satisfies the Schedule interface at compile time
+var _ Schedule = iso8601Schedule{}
+
+func (s iso8601Schedule) String() string {
+	return s.ri.String()
+}
+
+// Next returns the next time after prev the interval should fire
+func (s iso8601Schedule) Next(prev time.Time) time.Time {
+	return s.ri.After(prev)
+}
+
+// crontabSchedule is a Schedule backed by a parsed crontab expression,
+// supporting both 5/6-field specs and "@hourly"/"@daily"/"@weekly"/
+// "@monthly" shortcuts
+type crontabSchedule struct {
+	expr  string
+	sched cronexpr.Schedule
+}
+
+// assert crontabSchedule satisfies the Schedule interface at compile time
+var _ Schedule = crontabSchedule{}
+
+func (s crontabSchedule) String() string {
+	return s.expr
+}
+
+// Next returns the next activation time, later than prev
+func (s crontabSchedule) Next(prev time.Time) time.Time {
+	return s.sched.Next(prev)
+}
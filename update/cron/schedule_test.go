@@ -0,0 +1,61 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSchedule(t *testing.T) {
+	cases := []struct {
+		periodicity string
+		isISO8601   bool
+		err         string
+	}{
+		{"R/P1D", true, ""},
+		{"R/2020-01-01T00:00:00.000Z/P1D", true, ""},
+		{"0 * * * *", false, ""},
+		{"@hourly", false, ""},
+		{"not a schedule", false, `invalid periodicity "not a schedule": not a valid iso8601 interval or cron expression: expected exactly 5 fields, found 3: [not a schedule]`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.periodicity, func(t *testing.T) {
+			sched, err := NewSchedule(c.periodicity)
+			if c.err != "" {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if err.Error() != c.err {
+					t.Errorf("error mismatch. want: %q got: %q", c.err, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, isISO8601 := sched.(iso8601Schedule)
+			if isISO8601 != c.isISO8601 {
+				t.Errorf("expected isISO8601=%t, got %t", c.isISO8601, isISO8601)
+			}
+		})
+	}
+}
+
+func TestCrontabScheduleNext(t *testing.T) {
+	sched, err := NewSchedule("0 * * * *")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	prev := time.Date(2020, 1, 1, 12, 30, 0, 0, time.UTC)
+	next := sched.Next(prev)
+	want := time.Date(2020, 1, 1, 13, 0, 0, 0, time.UTC)
+	if !next.Equal(want) {
+		t.Errorf("next mismatch. want: %s got: %s", want, next)
+	}
+
+	if sched.String() != "0 * * * *" {
+		t.Errorf("String() mismatch. want: %q got: %q", "0 * * * *", sched.String())
+	}
+}
@@ -0,0 +1,140 @@
+package cron
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RunStatus describes where a job run is in its lifecycle
+type RunStatus string
+
+const (
+	// RSPending means a run has been recorded but hasn't started executing
+	RSPending = RunStatus("pending")
+	// RSRunning means a run is currently executing
+	RSRunning = RunStatus("running")
+	// RSSucceeded means a run completed without error
+	RSSucceeded = RunStatus("succeeded")
+	// RSFailed means a run completed with an error
+	RSFailed = RunStatus("failed")
+	// RSCancelled means a run was stopped before completing, eg by a panic
+	RSCancelled = RunStatus("cancelled")
+)
+
+// RunInfo is a single recorded execution of a job
+type RunInfo struct {
+	RunID      int
+	Status     RunStatus
+	Started    time.Time
+	Stopped    time.Time
+	ExitCode   int
+	Error      string
+	StderrTail string
+}
+
+// maxStderrTail bounds how much captured stderr a RunInfo keeps around
+const maxStderrTail = 2048
+
+// StatsManager tracks the run history & current status of every job. it's
+// backed by an in-memory map, keyed by job name, of the most recent runs
+type StatsManager struct {
+	lock    sync.Mutex
+	runID   int
+	history map[string][]*RunInfo
+}
+
+// NewStatsManager constructs a StatsManager ready for use
+func NewStatsManager() *StatsManager {
+	return &StatsManager{
+		history: map[string][]*RunInfo{},
+	}
+}
+
+// StartRun records a new run for a job in the pending state, returning the
+// RunInfo to be updated as the run progresses. callers should transition it
+// to running via MarkRunning once the job's command is actually invoked
+func (m *StatsManager) StartRun(name string) *RunInfo {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.runID++
+	info := &RunInfo{
+		RunID:  m.runID,
+		Status: RSPending,
+	}
+	m.history[name] = append(m.history[name], info)
+	return info
+}
+
+// MarkRunning transitions a pending run to running, recording its start
+// time
+func (m *StatsManager) MarkRunning(info *RunInfo) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	info.Status = RSRunning
+	info.Started = time.Now()
+}
+
+// FinishRun transitions a run to either succeeded or failed, attaching the
+// command's exit code and a tail of its captured stderr
+func (m *StatsManager) FinishRun(info *RunInfo, err error, exitCode int, stderrTail string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	info.Stopped = time.Now()
+	info.ExitCode = exitCode
+	if len(stderrTail) > maxStderrTail {
+		stderrTail = stderrTail[len(stderrTail)-maxStderrTail:]
+	}
+	info.StderrTail = stderrTail
+
+	if err != nil {
+		info.Status = RSFailed
+		info.Error = err.Error()
+		return
+	}
+	info.Status = RSSucceeded
+}
+
+// CancelRun transitions a run to cancelled, used when recovering from a
+// panic mid-execution
+func (m *StatsManager) CancelRun(info *RunInfo, recovered interface{}) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	info.Stopped = time.Now()
+	info.Status = RSCancelled
+	info.Error = fmt.Sprintf("recovered from panic: %v", recovered)
+}
+
+// Status returns the status of a job's most recent run
+func (m *StatsManager) Status(name string) (RunStatus, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	runs := m.history[name]
+	if len(runs) == 0 {
+		return "", fmt.Errorf("no runs recorded for job %q", name)
+	}
+	return runs[len(runs)-1].Status, nil
+}
+
+// History returns up to limit of a job's most recent runs, newest first.
+// limit <= 0 returns the full history
+func (m *StatsManager) History(name string, limit int) ([]*RunInfo, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	runs := m.history[name]
+	if limit <= 0 || limit > len(runs) {
+		limit = len(runs)
+	}
+
+	out := make([]*RunInfo, limit)
+	for i := 0; i < limit; i++ {
+		out[i] = runs[len(runs)-1-i]
+	}
+	return out, nil
+}
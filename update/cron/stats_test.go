@@ -0,0 +1,92 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStatsManagerLifecycle(t *testing.T) {
+	m := NewStatsManager()
+
+	if _, err := m.Status("me/ds"); err == nil {
+		t.Fatal("expected error fetching status of a job with no runs")
+	}
+
+	run := m.StartRun("me/ds")
+	if run.Status != RSPending {
+		t.Errorf("expected freshly started run to be pending, got %s", run.Status)
+	}
+	if status, err := m.Status("me/ds"); err != nil {
+		t.Fatal(err)
+	} else if status != RSPending {
+		t.Errorf("status mismatch. want: %s got: %s", RSPending, status)
+	}
+
+	m.MarkRunning(run)
+	if run.Status != RSRunning {
+		t.Errorf("expected marked run to be running, got %s", run.Status)
+	}
+	if run.Started.IsZero() {
+		t.Error("expected Started to be set after MarkRunning")
+	}
+
+	m.FinishRun(run, nil, 0, "")
+	if run.Status != RSSucceeded {
+		t.Errorf("expected finished run with no error to succeed, got %s", run.Status)
+	}
+	if run.Stopped.IsZero() {
+		t.Error("expected Stopped to be set after FinishRun")
+	}
+
+	failedRun := m.StartRun("me/ds")
+	m.MarkRunning(failedRun)
+	m.FinishRun(failedRun, fmt.Errorf("boom"), 1, "stderr output")
+	if failedRun.Status != RSFailed {
+		t.Errorf("expected finished run with error to fail, got %s", failedRun.Status)
+	}
+	if failedRun.Error != "boom" {
+		t.Errorf("error mismatch. want: %q got: %q", "boom", failedRun.Error)
+	}
+
+	cancelledRun := m.StartRun("me/ds")
+	m.MarkRunning(cancelledRun)
+	m.CancelRun(cancelledRun, "something went wrong")
+	if cancelledRun.Status != RSCancelled {
+		t.Errorf("expected cancelled run to be cancelled, got %s", cancelledRun.Status)
+	}
+
+	history, err := m.History("me/ds", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history entries, got %d", len(history))
+	}
+	if history[0].RunID != cancelledRun.RunID {
+		t.Errorf("expected newest-first ordering, got RunID %d first", history[0].RunID)
+	}
+
+	full, err := m.History("me/ds", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(full) != 3 {
+		t.Fatalf("expected full history of 3 runs, got %d", len(full))
+	}
+}
+
+func TestFinishRunTruncatesStderrTail(t *testing.T) {
+	m := NewStatsManager()
+	run := m.StartRun("me/ds")
+	m.MarkRunning(run)
+
+	long := make([]byte, maxStderrTail+100)
+	for i := range long {
+		long[i] = 'x'
+	}
+	m.FinishRun(run, fmt.Errorf("boom"), 1, string(long))
+
+	if len(run.StderrTail) != maxStderrTail {
+		t.Errorf("expected StderrTail truncated to %d bytes, got %d", maxStderrTail, len(run.StderrTail))
+	}
+}
@@ -0,0 +1,103 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemJobStore is an in-memory JobStore implementation, mainly for testing
+type MemJobStore struct {
+	lock sync.Mutex
+	jobs []*Job
+}
+
+// assert MemJobStore satisfies the JobStore interface at compile time
+var _ JobStore = (*MemJobStore)(nil)
+
+// Jobs lists jobs currently in the store, sorted by Name. returned Jobs are
+// copies, so callers are free to mutate them without racing the store's
+// own access to its internal slice
+func (s *MemJobStore) Jobs(offset, limit int) ([]*Job, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sort.Slice(s.jobs, func(i, j int) bool { return s.jobs[i].Name < s.jobs[j].Name })
+
+	if limit <= 0 || limit > len(s.jobs) {
+		limit = len(s.jobs)
+	}
+	if offset > len(s.jobs) {
+		offset = len(s.jobs)
+	}
+
+	jobs := make([]*Job, 0, limit)
+	for i := offset; i < len(s.jobs) && len(jobs) < limit; i++ {
+		job := *s.jobs[i]
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+// Job fetches a single job by name. the returned Job is a copy, so callers
+// are free to mutate it without racing the store's own access to its
+// internal slice
+func (s *MemJobStore) Job(name string) (*Job, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, job := range s.jobs {
+		if job.Name == name {
+			jobCopy := *job
+			return &jobCopy, nil
+		}
+	}
+	return nil, fmt.Errorf("job %q not found", name)
+}
+
+// PutJob places a job in the store, overwriting any existing job with the
+// same name
+func (s *MemJobStore) PutJob(job *Job) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i, j := range s.jobs {
+		if j.Name == job.Name {
+			s.jobs[i] = job
+			return nil
+		}
+	}
+	s.jobs = append(s.jobs, job)
+	return nil
+}
+
+// DeleteJob removes a job from the store
+func (s *MemJobStore) DeleteJob(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i, j := range s.jobs {
+		if j.Name == name {
+			s.jobs = append(s.jobs[:i], s.jobs[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("job %q not found", name)
+}
+
+// FlatbufferJobStore is a JobStore that persists jobs to a flatbuffer file
+// on disk
+type FlatbufferJobStore struct {
+	MemJobStore
+	path string
+}
+
+// assert FlatbufferJobStore satisfies the JobStore interface at compile time
+var _ JobStore = (*FlatbufferJobStore)(nil)
+
+// NewFlatbufferJobStore creates a job store, loading any existing jobs
+// from the flatbuffer file at path
+func NewFlatbufferJobStore(path string) *FlatbufferJobStore {
+	// TODO (b5) - load existing jobs from path, persist on every Put/Delete
+	return &FlatbufferJobStore{path: path}
+}
@@ -0,0 +1,124 @@
+package cron
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// webhookPayloadKey is the context key a webhook's decoded JSON body is
+// stored under, read back out by JobToCmd when building the job's command
+type webhookPayloadKey struct{}
+
+// PayloadFromContext returns the JSON payload of the webhook request that
+// triggered the current run, if any
+func PayloadFromContext(ctx context.Context) (map[string]string, bool) {
+	payload, ok := ctx.Value(webhookPayloadKey{}).(map[string]string)
+	return payload, ok
+}
+
+// webhookHandler handles POST /webhook/{jobName}, allowing external
+// systems (git push hooks, upstream data producers) to trigger a job
+// on-demand instead of waiting for its schedule to elapse
+func (c *Cron) webhookHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/webhook/")
+	if name == "" {
+		http.Error(w, "job name is required", http.StatusBadRequest)
+		return
+	}
+
+	job, err := c.jobStore.Job(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if job.Webhook == nil {
+		http.Error(w, fmt.Sprintf("job %q does not accept webhooks", name), http.StatusForbidden)
+		return
+	}
+
+	if err := checkSourceIP(r, job.Webhook.AllowedIPs); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := checkSignature(job.Webhook.Secret, r.Header.Get("X-Qri-Signature"), body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	payload := map[string]string{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, fmt.Sprintf("invalid webhook body: %s", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx := context.WithValue(r.Context(), webhookPayloadKey{}, payload)
+	out, err := c.RunJob(ctx, name)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	jsonResponse(w, out)
+}
+
+// checkSignature validates the HMAC-SHA256 signature of a webhook body
+// against the job's configured secret. an empty secret disables
+// validation entirely
+func checkSignature(secret, sig string, body []byte) error {
+	if secret == "" {
+		return nil
+	}
+	if sig == "" {
+		return fmt.Errorf("missing X-Qri-Signature header")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return fmt.Errorf("invalid webhook signature")
+	}
+	return nil
+}
+
+// checkSourceIP confirms the request's remote address is in allowed, an
+// empty allow-list permits any source
+func checkSourceIP(r *http.Request, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	for _, ip := range allowed {
+		if ip == host {
+			return nil
+		}
+	}
+	return fmt.Errorf("source IP %q is not allowed to trigger this webhook", host)
+}
@@ -0,0 +1,102 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestCheckSignature(t *testing.T) {
+	body := []byte(`{"key":"value"}`)
+
+	if err := checkSignature("", "", body); err != nil {
+		t.Errorf("expected no error when secret is empty, got: %s", err)
+	}
+	if err := checkSignature("secret", "", body); err == nil {
+		t.Error("expected error when signature header is missing")
+	}
+	if err := checkSignature("secret", "deadbeef", body); err == nil {
+		t.Error("expected error for mismatched signature")
+	}
+	if err := checkSignature("secret", sign("secret", body), body); err != nil {
+		t.Errorf("expected valid signature to pass, got: %s", err)
+	}
+}
+
+func TestCheckSourceIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/webhook/me/ds", nil)
+	req.RemoteAddr = "1.2.3.4:5555"
+
+	if err := checkSourceIP(req, nil); err != nil {
+		t.Errorf("expected no error with an empty allow-list, got: %s", err)
+	}
+	if err := checkSourceIP(req, []string{"1.2.3.4"}); err != nil {
+		t.Errorf("expected allowed IP to pass, got: %s", err)
+	}
+	if err := checkSourceIP(req, []string{"9.9.9.9"}); err == nil {
+		t.Error("expected disallowed IP to be rejected")
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	c := newTestCron()
+	sched, err := NewSchedule("@hourly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := &Job{
+		Name:        "me/mydataset",
+		Type:        JTShellScript,
+		Periodicity: sched,
+		Webhook:     &WebhookConfig{Secret: "topsecret"},
+	}
+	if err := c.jobStore.PutJob(job); err != nil {
+		t.Fatal(err)
+	}
+
+	body := []byte(`{"foo":"bar"}`)
+
+	s := httptest.NewServer(http.HandlerFunc(c.webhookHandler))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.URL+"/webhook/me/mydataset", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Qri-Signature", sign("topsecret", body))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	req2, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.URL+"/webhook/me/mydataset", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req2.Header.Set("X-Qri-Signature", "bogus")
+
+	resp2, err := http.DefaultClient.Do(req2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for bad signature, got %d", resp2.StatusCode)
+	}
+}
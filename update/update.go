@@ -14,8 +14,9 @@ import (
 	golog "github.com/ipfs/go-log"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/ioes"
-	"github.com/qri-io/iso8601"
 	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/event"
 	"github.com/qri-io/qri/update/cron"
 )
 
@@ -33,8 +34,11 @@ func Path(repoPath string) (path string, err error) {
 	return
 }
 
-// Start starts the update service
-func Start(ctx context.Context, repoPath string, updateCfg *config.Update, daemonize bool) error {
+// Start starts the update service. announcer is optional (may be nil) and,
+// when provided by the caller (the lib layer, which owns the p2p.QriNode),
+// is used to broadcast successful dataset saves to the network - see
+// DatasetAnnouncer
+func Start(ctx context.Context, repoPath string, updateCfg *config.Update, daemonize bool, announcer DatasetAnnouncer) error {
 	if updateCfg == nil {
 		updateCfg = config.DefaultUpdate()
 	}
@@ -48,7 +52,7 @@ func Start(ctx context.Context, repoPath string, updateCfg *config.Update, daemo
 		return daemonInstall(repoPath)
 	}
 
-	return start(ctx, repoPath, updateCfg)
+	return start(ctx, repoPath, updateCfg, announcer)
 }
 
 // StopDaemon checks for a running daemon, uninstalling it if one exists
@@ -61,7 +65,7 @@ func Status() (string, error) {
 	return daemonShow()
 }
 
-func start(ctx context.Context, repoPath string, updateCfg *config.Update) error {
+func start(ctx context.Context, repoPath string, updateCfg *config.Update, announcer DatasetAnnouncer) error {
 	path, err := Path(repoPath)
 	if err != nil {
 		return err
@@ -79,7 +83,9 @@ func start(ctx context.Context, repoPath string, updateCfg *config.Update) error
 		return fmt.Errorf("unknown cron type: %s", updateCfg.Type)
 	}
 
-	svc := cron.NewCron(jobStore, logStore, Factory)
+	bus := event.NewBus(ctx)
+	svc := cron.NewCron(jobStore, logStore, Factory(announcer, bus))
+	svc.SetBus(bus)
 	log.Debug("starting update service")
 	go func() {
 		if err := svc.ServeHTTP(updateCfg.Address); err != nil {
@@ -90,46 +96,139 @@ func start(ctx context.Context, repoPath string, updateCfg *config.Update) error
 	return svc.Start(ctx)
 }
 
-// Factory returns a function that can run jobs
-func Factory(context.Context) cron.RunJobFunc {
-	return func(ctx context.Context, streams ioes.IOStreams, job *cron.Job) error {
-		log.Debugf("running update: %s", job.Name)
+// DatasetAnnouncer is implemented by p2p.QriNode. Separating it out as an
+// interface lets the update package broadcast newly saved dataset versions
+// to the network without depending on all of p2p
+type DatasetAnnouncer interface {
+	AnnounceDatasetUpdated(ref dsref.Ref, newHead string) error
+}
 
-		var errBuf *bytes.Buffer
-		// if the job type is a dataset, error output is semi-predictable
-		// write to a buffer for better error reporting
-		if job.Type == cron.JTDataset {
-			errBuf = &bytes.Buffer{}
+// Factory returns a cron.Factory that runs jobs, recording status
+// transitions to the given StatsManager as it drives the job's command,
+// broadcasting successful dataset saves via announcer, and publishing
+// ETUpdateJob* events to bus. announcer and bus may be nil, in which case
+// broadcasting / publishing is skipped
+func Factory(announcer DatasetAnnouncer, bus event.Bus) cron.Factory {
+	return func(_ context.Context, stats *cron.StatsManager) cron.RunJobFunc {
+		return func(ctx context.Context, streams ioes.IOStreams, job *cron.Job) (err error) {
+			log.Debugf("running update: %s", job.Name)
+
+			errBuf := &bytes.Buffer{}
 			teedErrOut := io.MultiWriter(streams.ErrOut, errBuf)
-			streams = ioes.NewIOStreams(streams.In, streams.Out, teedErrOut)
-		}
 
-		cmd := JobToCmd(streams, job)
-		if cmd == nil {
-			return fmt.Errorf("unrecognized update type: %s", job.Type)
+			outBuf := &bytes.Buffer{}
+			teedOut := io.MultiWriter(streams.Out, outBuf)
+
+			streams = ioes.NewIOStreams(streams.In, teedOut, teedErrOut)
+
+			cmd := JobToCmd(ctx, streams, job)
+			if cmd == nil {
+				return fmt.Errorf("unrecognized update type: %s", job.Type)
+			}
+
+			run := stats.StartRun(job.Name)
+			publish(ctx, bus, event.ETUpdateJobStarted, event.UpdateJobEvent{Name: job.Name, RunID: run.RunID})
+
+			defer func() {
+				if r := recover(); r != nil {
+					stats.CancelRun(run, r)
+					err = fmt.Errorf("recovered from panic running %q: %v", job.Name, r)
+					publish(ctx, bus, event.ETUpdateJobFailed, event.UpdateJobEvent{Name: job.Name, RunID: run.RunID, Err: err.Error()})
+				}
+			}()
+
+			stats.MarkRunning(run)
+			runErr := cmd.Run()
+			err = processJobError(job, errBuf, runErr)
+			stats.FinishRun(run, err, exitCode(cmd), errBuf.String())
+			duration := run.Stopped.Sub(run.Started)
+
+			switch {
+			case err == nil:
+				publish(ctx, bus, event.ETUpdateJobSucceeded, event.UpdateJobEvent{Name: job.Name, RunID: run.RunID, Duration: duration})
+				if announcer != nil && job.Type == cron.JTDataset {
+					announceDatasetUpdated(announcer, job, outBuf)
+				}
+			case err == ErrNoChanges:
+				publish(ctx, bus, event.ETUpdateJobNoChanges, event.UpdateJobEvent{Name: job.Name, RunID: run.RunID, Duration: duration})
+			default:
+				publish(ctx, bus, event.ETUpdateJobFailed, event.UpdateJobEvent{Name: job.Name, RunID: run.RunID, Duration: duration, Err: err.Error()})
+			}
+
+			return err
 		}
+	}
+}
+
+// publish sends an event on bus, swallowing (and logging) any handler
+// error since a failed subscriber shouldn't fail the run it's reacting to.
+// a nil bus is a no-op, allowing Factory to be used without one wired up
+func publish(ctx context.Context, bus event.Bus, topic event.Topic, payload event.UpdateJobEvent) {
+	if bus == nil {
+		return
+	}
+	if err := bus.Publish(ctx, topic, payload); err != nil {
+		log.Debugf("publishing %q event: %s", topic, err)
+	}
+}
+
+// announceDatasetUpdated parses the job's dataset reference and the new
+// head it was saved to out of a successful "qri save" run's stdout, then
+// broadcasts the update via announcer
+func announceDatasetUpdated(announcer DatasetAnnouncer, job *cron.Job, out *bytes.Buffer) {
+	ref, err := dsref.Parse(job.Name)
+	if err != nil {
+		log.Debugf("parsing job name %q as a dataset reference: %s", job.Name, err)
+		return
+	}
+
+	// TODO (b5) - "qri save" needs a --format=json or similar flag so this
+	// doesn't rely on scraping its last line of output for the new head
+	newHead := strings.TrimSpace(lastLine(out.String()))
+	if newHead == "" {
+		return
+	}
+
+	if err := announcer.AnnounceDatasetUpdated(ref, newHead); err != nil {
+		log.Debugf("announcing dataset update for %q: %s", job.Name, err)
+	}
+}
+
+// lastLine returns the last non-empty line of s
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	return lines[len(lines)-1]
+}
 
-		err := cmd.Run()
-		return processJobError(job, errBuf, err)
+// exitCode extracts a command's exit code after it's been run, defaulting
+// to 0 when no exit code is available (eg the process never started)
+func exitCode(cmd *exec.Cmd) int {
+	if cmd.ProcessState == nil {
+		return 0
 	}
+	return cmd.ProcessState.ExitCode()
 }
 
 // JobToCmd returns an operating system command that will execute the given job
-// wiring operating system in/out/errout to the provided iostreams.
-func JobToCmd(streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
+// wiring operating system in/out/errout to the provided iostreams. ctx is
+// consulted for a webhook payload (see cron.PayloadFromContext) when the
+// job was triggered via the cron service's webhook endpoint
+func JobToCmd(ctx context.Context, streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
 	switch job.Type {
 	case cron.JTDataset:
-		return datasetSaveCmd(streams, job)
+		return datasetSaveCmd(ctx, streams, job)
 	case cron.JTShellScript:
-		return shellScriptCmd(streams, job)
+		return shellScriptCmd(ctx, streams, job)
 	default:
 		return nil
 	}
 }
 
 // datasetSaveCmd configures a "qri save" command based on job details
-// wiring operating system in/out/errout to the provided iostreams.
-func datasetSaveCmd(streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
+// wiring operating system in/out/errout to the provided iostreams. a
+// webhook payload on ctx is forwarded as repeated --secrets=key=value
+// flags, for use by the save's transform/recall steps
+func datasetSaveCmd(ctx context.Context, streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
 	args := []string{"save", job.Name}
 
 	if job.RepoPath != "" {
@@ -155,7 +254,7 @@ func datasetSaveCmd(streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
 			}
 		}
 
-		// TODO (b5) - config and secrets
+		// TODO (b5) - config
 
 		boolFlags := map[string]bool{
 			"--publish":     o.Publish,
@@ -171,6 +270,12 @@ func datasetSaveCmd(streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
 		}
 	}
 
+	if payload, ok := cron.PayloadFromContext(ctx); ok {
+		for k, v := range payload {
+			args = append(args, fmt.Sprintf(`--secrets=%s=%s`, k, v))
+		}
+	}
+
 	cmd := exec.Command("qri", args...)
 	cmd.Stderr = streams.ErrOut
 	cmd.Stdout = streams.Out
@@ -181,11 +286,18 @@ func datasetSaveCmd(streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
 // shellScriptCmd creates an exec.Cmd, wires operating system in/out/errout
 // to the provided iostreams.
 // Commands are executed with access to the same enviornment variables as the
-// process the runner is executing in
-func shellScriptCmd(streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
-	// TODO (b5) - config and secrets as env vars
-
+// process the runner is executing in, plus any webhook payload forwarded
+// as additional env vars
+func shellScriptCmd(ctx context.Context, streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
 	cmd := exec.Command(job.Name)
+	if payload, ok := cron.PayloadFromContext(ctx); ok {
+		env := os.Environ()
+		for k, v := range payload {
+			env = append(env, fmt.Sprintf("%s=%s", k, v))
+		}
+		cmd.Env = env
+	}
+
 	cmd.Stderr = streams.ErrOut
 	cmd.Stdout = streams.Out
 	cmd.Stdin = streams.In
@@ -198,7 +310,9 @@ func PossibleShellScript(path string) bool {
 	return filepath.Ext(path) == ".sh"
 }
 
-// DatasetToJob converts a dataset to cron.Job
+// DatasetToJob converts a dataset to cron.Job. periodicity may be either an
+// ISO-8601 repeating interval (eg "R/P1D") or a crontab expression (eg
+// "30 * * * *", "@hourly") - see cron.NewSchedule
 func DatasetToJob(ds *dataset.Dataset, periodicity string, opts *cron.DatasetOptions) (job *cron.Job, err error) {
 	if periodicity == "" && ds.Meta != nil && ds.Meta.AccrualPeriodicity != "" {
 		periodicity = ds.Meta.AccrualPeriodicity
@@ -208,7 +322,7 @@ func DatasetToJob(ds *dataset.Dataset, periodicity string, opts *cron.DatasetOpt
 		return nil, fmt.Errorf("scheduling dataset updates requires a meta component with accrualPeriodicity set")
 	}
 
-	p, err := iso8601.ParseRepeatingInterval(periodicity)
+	sched, err := cron.NewSchedule(periodicity)
 	if err != nil {
 		return nil, err
 	}
@@ -216,7 +330,7 @@ func DatasetToJob(ds *dataset.Dataset, periodicity string, opts *cron.DatasetOpt
 	job = &cron.Job{
 		// TODO (b5) - dataset.Dataset needs an Alias() method:
 		Name:         fmt.Sprintf("%s/%s", ds.Peername, ds.Name),
-		Periodicity:  p,
+		Periodicity:  sched,
 		Type:         cron.JTDataset,
 		PrevRunStart: ds.Commit.Timestamp,
 	}
@@ -228,9 +342,11 @@ func DatasetToJob(ds *dataset.Dataset, periodicity string, opts *cron.DatasetOpt
 	return
 }
 
-// ShellScriptToJob turns a shell script into cron.Job
+// ShellScriptToJob turns a shell script into cron.Job. periodicity may be
+// either an ISO-8601 repeating interval or a crontab expression - see
+// cron.NewSchedule
 func ShellScriptToJob(path string, periodicity string, opts *cron.ShellScriptOptions) (job *cron.Job, err error) {
-	p, err := iso8601.ParseRepeatingInterval(periodicity)
+	sched, err := cron.NewSchedule(periodicity)
 	if err != nil {
 		return nil, err
 	}
@@ -239,7 +355,7 @@ func ShellScriptToJob(path string, periodicity string, opts *cron.ShellScriptOpt
 
 	job = &cron.Job{
 		Name:        path,
-		Periodicity: p,
+		Periodicity: sched,
 		Type:        cron.JTShellScript,
 	}
 	if opts != nil {
@@ -249,6 +365,10 @@ func ShellScriptToJob(path string, periodicity string, opts *cron.ShellScriptOpt
 	return
 }
 
+// ErrNoChanges indicates a dataset job ran successfully but found nothing
+// new worth saving
+var ErrNoChanges = fmt.Errorf("no changes to save")
+
 func processJobError(job *cron.Job, errOut *bytes.Buffer, err error) error {
 	if err == nil {
 		return nil
@@ -259,7 +379,7 @@ func processJobError(job *cron.Job, errOut *bytes.Buffer, err error) error {
 		if strings.Contains(errOut.String(), "no changes to save") {
 			// TODO (b5) - this should be a concrete error declared in dsfs:
 			// dsfs.ErrNoChanges
-			return fmt.Errorf("no changes to save")
+			return ErrNoChanges
 		}
 	}
 
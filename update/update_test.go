@@ -0,0 +1,216 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/event"
+	"github.com/qri-io/qri/update/cron"
+)
+
+// eventRecorder is a minimal event.Bus that records every event published
+// to it, optionally panicking when a configured topic fires - used to
+// exercise Factory's own panic recovery
+type eventRecorder struct {
+	events  []event.Event
+	panicOn event.Topic
+}
+
+func (b *eventRecorder) Publish(ctx context.Context, topic event.Topic, payload interface{}) error {
+	if topic == b.panicOn {
+		panic("simulated subscriber panic")
+	}
+	b.events = append(b.events, event.Event{Topic: topic, Payload: payload})
+	return nil
+}
+
+func (b *eventRecorder) Subscribe(handler event.Handler, topics ...event.Topic) {}
+
+func (b *eventRecorder) topics() []event.Topic {
+	topics := make([]event.Topic, len(b.events))
+	for i, e := range b.events {
+		topics[i] = e.Topic
+	}
+	return topics
+}
+
+type fakeAnnouncer struct {
+	ref     dsref.Ref
+	newHead string
+	called  bool
+}
+
+func (a *fakeAnnouncer) AnnounceDatasetUpdated(ref dsref.Ref, newHead string) error {
+	a.called = true
+	a.ref = ref
+	a.newHead = newHead
+	return nil
+}
+
+// scriptJob writes a shell script with the given body to a temp file and
+// returns a JTShellScript job pointing at it
+func scriptJob(t *testing.T, body string) *cron.Job {
+	t.Helper()
+	f, err := ioutil.TempFile("", "qri-update-test-*.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("#!/bin/sh\n" + body); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if err := os.Chmod(f.Name(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Remove(f.Name()) })
+
+	sched, err := cron.NewSchedule("@hourly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &cron.Job{Name: f.Name(), Type: cron.JTShellScript, Periodicity: sched}
+}
+
+func TestFactorySuccessPublishesStartedThenSucceeded(t *testing.T) {
+	bus := &eventRecorder{}
+	runner := Factory(nil, bus)(context.Background(), cron.NewStatsManager())
+
+	job := scriptJob(t, "exit 0\n")
+	if err := runner(context.Background(), ioes.NewDiscardIOStreams(), job); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []event.Topic{event.ETUpdateJobStarted, event.ETUpdateJobSucceeded}
+	assertTopics(t, bus.topics(), want)
+}
+
+func TestFactoryFailurePublishesFailed(t *testing.T) {
+	bus := &eventRecorder{}
+	runner := Factory(nil, bus)(context.Background(), cron.NewStatsManager())
+
+	job := scriptJob(t, "echo boom 1>&2\nexit 1\n")
+	if err := runner(context.Background(), ioes.NewDiscardIOStreams(), job); err == nil {
+		t.Fatal("expected an error from a failing job")
+	}
+
+	want := []event.Topic{event.ETUpdateJobStarted, event.ETUpdateJobFailed}
+	assertTopics(t, bus.topics(), want)
+}
+
+func TestFactoryRecoversSubscriberPanic(t *testing.T) {
+	// panic when the success event fires, simulating a misbehaving
+	// subscriber - the run has already completed successfully by then, so
+	// StartRun has already produced a non-nil *RunInfo for CancelRun to
+	// operate on
+	bus := &eventRecorder{panicOn: event.ETUpdateJobSucceeded}
+	runner := Factory(nil, bus)(context.Background(), cron.NewStatsManager())
+
+	job := scriptJob(t, "exit 0\n")
+	err := runner(context.Background(), ioes.NewDiscardIOStreams(), job)
+	if err == nil {
+		t.Fatal("expected the recovered panic to surface as an error")
+	}
+
+	want := []event.Topic{event.ETUpdateJobStarted, event.ETUpdateJobFailed}
+	assertTopics(t, bus.topics(), want)
+}
+
+func TestExitCode(t *testing.T) {
+	cmd := exec.Command("/bin/sh", "-c", "exit 3")
+	cmd.Run()
+	if code := exitCode(cmd); code != 3 {
+		t.Errorf("exit code mismatch. want: 3 got: %d", code)
+	}
+
+	neverRun := exec.Command("/bin/true")
+	if code := exitCode(neverRun); code != 0 {
+		t.Errorf("expected exit code 0 for a command with no ProcessState, got %d", code)
+	}
+}
+
+func TestProcessJobError(t *testing.T) {
+	cases := []struct {
+		name   string
+		job    *cron.Job
+		errOut string
+		in     error
+		want   error
+	}{
+		{"no error", &cron.Job{Type: cron.JTDataset}, "", nil, nil},
+		{"dataset no changes", &cron.Job{Type: cron.JTDataset}, "some log line\nno changes to save\n", fmt.Errorf("exit status 1"), ErrNoChanges},
+		{"dataset other error", &cron.Job{Type: cron.JTDataset}, "some other failure\n", fmt.Errorf("exit status 1"), fmt.Errorf("exit status 1")},
+		{"shell script ignores the no-changes marker", &cron.Job{Type: cron.JTShellScript}, "no changes to save\n", fmt.Errorf("boom"), fmt.Errorf("boom")},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := processJobError(c.job, bytes.NewBufferString(c.errOut), c.in)
+			if (got == nil) != (c.want == nil) {
+				t.Fatalf("error mismatch. want: %v got: %v", c.want, got)
+			}
+			if got != nil && got.Error() != c.want.Error() {
+				t.Errorf("error mismatch. want: %q got: %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestAnnounceDatasetUpdated(t *testing.T) {
+	announcer := &fakeAnnouncer{}
+	job := &cron.Job{Name: "me/mydataset", Type: cron.JTDataset}
+	out := bytes.NewBufferString("saved dataset\n\nQmNewHeadHash\n")
+
+	announceDatasetUpdated(announcer, job, out)
+
+	if !announcer.called {
+		t.Fatal("expected announcer to be called")
+	}
+	if announcer.newHead != "QmNewHeadHash" {
+		t.Errorf("newHead mismatch. want: %q got: %q", "QmNewHeadHash", announcer.newHead)
+	}
+	if announcer.ref.String() != "me/mydataset" {
+		t.Errorf("ref mismatch. want: %q got: %q", "me/mydataset", announcer.ref.String())
+	}
+}
+
+func TestAnnounceDatasetUpdatedSkipsEmptyOutput(t *testing.T) {
+	announcer := &fakeAnnouncer{}
+	job := &cron.Job{Name: "me/mydataset", Type: cron.JTDataset}
+
+	announceDatasetUpdated(announcer, job, &bytes.Buffer{})
+
+	if announcer.called {
+		t.Error("expected announcer not to be called when the run produced no output")
+	}
+}
+
+func TestAnnounceDatasetUpdatedSkipsUnparsableName(t *testing.T) {
+	announcer := &fakeAnnouncer{}
+	job := &cron.Job{Name: "not a valid ref", Type: cron.JTDataset}
+	out := bytes.NewBufferString("QmNewHeadHash\n")
+
+	announceDatasetUpdated(announcer, job, out)
+
+	if announcer.called {
+		t.Error("expected announcer not to be called for an unparsable job name")
+	}
+}
+
+func assertTopics(t *testing.T, got, want []event.Topic) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("topic count mismatch. want: %v got: %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("topic %d mismatch. want: %s got: %s", i, want[i], got[i])
+		}
+	}
+}